@@ -1,11 +1,10 @@
 package validation
 
 import (
-	"encoding/hex"
+	"context"
 	"time"
 
 	"github.com/bytom/consensus"
-	"github.com/bytom/crypto/ed25519/chainkd"
 	"github.com/bytom/errors"
 	"github.com/bytom/protocol/bc"
 	"github.com/bytom/protocol/bc/types"
@@ -23,7 +22,10 @@ var (
 	errVersionRegression     = errors.New("version regression")
 )
 
-func checkBlockTime(b *bc.Block, parent *state.BlockNode) error {
+func checkBlockTime(ctx context.Context, b *bc.Block, parent *state.BlockNode) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if b.Timestamp > uint64(time.Now().Unix())+consensus.MaxTimeOffsetSeconds {
 		return errBadTimestamp
 	}
@@ -34,7 +36,10 @@ func checkBlockTime(b *bc.Block, parent *state.BlockNode) error {
 	return nil
 }
 
-func checkCoinbaseAmount(b *bc.Block, amount uint64) error {
+func checkCoinbaseAmount(ctx context.Context, b *bc.Block, amount uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if len(b.Transactions) == 0 {
 		return errors.Wrap(ErrWrongCoinbaseTransaction, "block is empty")
 	}
@@ -51,66 +56,86 @@ func checkCoinbaseAmount(b *bc.Block, amount uint64) error {
 	return nil
 }
 
-// ValidateBlockHeader check the block's header
-func ValidateBlockHeader(b *bc.Block, parent *state.BlockNode) error {
+// ValidateBlockHeader check the block's header against parent, dispatching
+// the difficulty-bits check to engine.
+func ValidateBlockHeader(ctx context.Context, b *bc.Block, parent *state.BlockNode, engine ConsensusEngine) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if b.Version < parent.Version {
 		return errors.WithDetailf(errVersionRegression, "previous block verson %d, current block version %d", parent.Version, b.Version)
 	}
 	if b.Height != parent.Height+1 {
 		return errors.WithDetailf(errMisorderedBlockHeight, "previous block height %d, current block height %d", parent.Height, b.Height)
 	}
-	if b.Bits != parent.CalcNextBits() {
-		return errBadBits
+	if err := engine.CheckBits(b, parent); err != nil {
+		return err
 	}
 	if parent.Hash != *b.PreviousBlockId {
 		return errors.WithDetailf(errMismatchedBlock, "previous block ID %x, current block wants %x", parent.Hash.Bytes(), b.PreviousBlockId.Bytes())
 	}
-	if err := checkBlockTime(b, parent); err != nil {
+	if err := checkBlockTime(ctx, b, parent); err != nil {
 		return err
 	}
 	return nil
 }
 
-// ValidateBlock validates a block and the transactions within.
-func ValidateBlock(b *bc.Block, parent *state.BlockNode, block *types.Block, authoritys map[string]string, position uint64) error {
-	if err := ValidateBlockHeader(b, parent); err != nil {
-		return err
+// ValidateBlock validates a block and the transactions within, using
+// engine to check the block's proof and coinbase reward. Passing nil
+// uses NewAuthoritySignEngine(nil): authority-signature checking with an
+// empty authority map, so no control program has a registered xpub.
+func ValidateBlock(ctx context.Context, b *bc.Block, parent *state.BlockNode, block *types.Block, engine ConsensusEngine, observer ValidationObserver) error {
+	return validateBlock(ctx, b, parent, block, engine, ValidationOptions{}, observer)
+}
+
+// validateBlock is the shared implementation behind ValidateBlock and
+// ValidateBlockParallel; opts.Parallel picks which way the transaction
+// loop runs. It honors ctx.Done() between transactions and reports
+// progress through observer, defaulting to NopObserver when nil.
+func validateBlock(ctx context.Context, b *bc.Block, parent *state.BlockNode, block *types.Block, engine ConsensusEngine, opts ValidationOptions, observer ValidationObserver) (err error) {
+	if engine == nil {
+		engine = NewAuthoritySignEngine(nil)
 	}
-	// 验证出块人
-	controlProgram := string(b.GetProof().GetControlProgram())
-	var xpub chainkd.XPub
-	tmp, err := hex.DecodeString(authoritys[controlProgram])
-	if err != nil {
-		return err
+	if observer == nil {
+		observer = NopObserver{}
 	}
+	defer func() {
+		if err != nil {
+			observer.OnBlockRejected(b, err)
+		}
+	}()
 
-	copy(xpub[:], tmp[:])
-	msg, _ := block.MarshalText()
-	sign := b.GetProof().GetSign()
-	if !xpub.Verify(msg, sign) {
-		return errors.New("Verification signature failed")
+	if err := ValidateBlockHeader(ctx, b, parent, engine); err != nil {
+		return err
+	}
+	observer.OnHeaderChecked(b)
+	if err := engine.VerifyBlockProof(b, parent, block); err != nil {
+		return err
 	}
 
 	blockGasSum := uint64(0)
-	coinbaseAmount := consensus.BlockSubsidy(b.BlockHeader.Height)
+	coinbaseAmount := engine.ExpectedCoinbaseReward(b.BlockHeader.Height)
 	b.TransactionStatus = bc.NewTransactionStatus()
 
-	for i, tx := range b.Transactions {
-		gasStatus, err := ValidateTx(tx, b)
-		if !gasStatus.GasValid {
-			return errors.Wrapf(err, "validate of transaction %d of %d", i, len(b.Transactions))
+	results := validateTransactions(ctx, b, opts, observer)
+	for i, result := range results {
+		if result.err == context.Canceled || result.err == context.DeadlineExceeded {
+			return result.err
+		}
+		if !result.gasStatus.GasValid {
+			return errors.Wrapf(result.err, "validate of transaction %d of %d", i, len(b.Transactions))
 		}
 
-		if err := b.TransactionStatus.SetStatus(i, err != nil); err != nil {
+		if err := b.TransactionStatus.SetStatus(i, result.err != nil); err != nil {
 			return err
 		}
-		coinbaseAmount += gasStatus.BTMValue
-		if blockGasSum += uint64(gasStatus.GasUsed); blockGasSum > consensus.MaxBlockGas {
+		coinbaseAmount += result.gasStatus.BTMValue
+		if blockGasSum += uint64(result.gasStatus.GasUsed); blockGasSum > consensus.MaxBlockGas {
 			return errOverBlockLimit
 		}
 	}
 
-	if err := checkCoinbaseAmount(b, coinbaseAmount); err != nil {
+	if err := checkCoinbaseAmount(ctx, b, coinbaseAmount); err != nil {
 		return err
 	}
 
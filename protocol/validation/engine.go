@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"encoding/hex"
+
+	"github.com/bytom/consensus"
+	"github.com/bytom/crypto/ed25519/chainkd"
+	"github.com/bytom/errors"
+	"github.com/bytom/protocol/bc"
+	"github.com/bytom/protocol/bc/types"
+	"github.com/bytom/protocol/state"
+)
+
+// ConsensusEngine abstracts the rules that decide whether a block's proof
+// is valid, what reward its coinbase may claim, and how its difficulty
+// bits are checked. Different consensus models (a fixed authority list,
+// proof-of-work, a rotating committee, ...) implement this interface so
+// that ValidateBlock/ValidateBlockHeader don't have to hard-code one of
+// them.
+type ConsensusEngine interface {
+	// VerifyBlockProof checks that b carries a valid proof under this
+	// engine's rules (an authority signature, a PoW solution, a quorum
+	// certificate, ...). raw is the original wire-format block, which
+	// some engines need in order to recompute a signed message.
+	VerifyBlockProof(b *bc.Block, parent *state.BlockNode, raw *types.Block) error
+
+	// ExpectedCoinbaseReward returns the subsidy a coinbase transaction
+	// at the given height is allowed to claim, before transaction fees.
+	ExpectedCoinbaseReward(height uint64) uint64
+
+	// CheckBits validates that b's difficulty bits are consistent with
+	// parent under this engine's rule. Engines that don't adjust a
+	// difficulty target (e.g. authority signing) can return nil.
+	CheckBits(b *bc.Block, parent *state.BlockNode) error
+}
+
+// AuthoritySignEngine is the consensus engine bytom has always shipped
+// with: a block is valid if it carries a signature, from the xpub
+// registered for the block's control program, over the block itself.
+type AuthoritySignEngine struct {
+	authoritys map[string]string
+}
+
+// NewAuthoritySignEngine returns an AuthoritySignEngine that trusts the
+// xpubs in authoritys, keyed by hex-encoded control program.
+func NewAuthoritySignEngine(authoritys map[string]string) *AuthoritySignEngine {
+	return &AuthoritySignEngine{authoritys: authoritys}
+}
+
+// VerifyBlockProof checks the signature carried in b's proof against the
+// xpub registered for its control program.
+func (e *AuthoritySignEngine) VerifyBlockProof(b *bc.Block, parent *state.BlockNode, raw *types.Block) error {
+	controlProgram := string(b.GetProof().GetControlProgram())
+	var xpub chainkd.XPub
+	tmp, err := hex.DecodeString(e.authoritys[controlProgram])
+	if err != nil {
+		return err
+	}
+
+	copy(xpub[:], tmp[:])
+	msg, _ := raw.MarshalText()
+	sign := b.GetProof().GetSign()
+	if !xpub.Verify(msg, sign) {
+		return errors.New("Verification signature failed")
+	}
+	return nil
+}
+
+// ExpectedCoinbaseReward defers to the standard subsidy schedule.
+func (e *AuthoritySignEngine) ExpectedCoinbaseReward(height uint64) uint64 {
+	return consensus.BlockSubsidy(height)
+}
+
+// CheckBits requires Bits to stay whatever CalcNextBits reports, since
+// authority signing doesn't adjust difficulty.
+func (e *AuthoritySignEngine) CheckBits(b *bc.Block, parent *state.BlockNode) error {
+	if b.Bits != parent.CalcNextBits() {
+		return errBadBits
+	}
+	return nil
+}
+
+// PoWEngine verifies blocks purely by proof-of-work: Bits must follow the
+// parent's difficulty-adjustment rule, and the block hash must satisfy
+// the target Bits implies.
+type PoWEngine struct{}
+
+// NewPoWEngine returns a PoWEngine.
+func NewPoWEngine() *PoWEngine {
+	return &PoWEngine{}
+}
+
+// VerifyBlockProof is a no-op for PoWEngine: the mining puzzle itself is
+// the proof, and it was already checked by CheckBits.
+func (e *PoWEngine) VerifyBlockProof(b *bc.Block, parent *state.BlockNode, raw *types.Block) error {
+	return nil
+}
+
+// ExpectedCoinbaseReward defers to the standard subsidy schedule.
+func (e *PoWEngine) ExpectedCoinbaseReward(height uint64) uint64 {
+	return consensus.BlockSubsidy(height)
+}
+
+// CheckBits requires Bits to follow the parent's difficulty adjustment
+// and that b's hash actually satisfies the target those bits imply.
+func (e *PoWEngine) CheckBits(b *bc.Block, parent *state.BlockNode) error {
+	if b.Bits != parent.CalcNextBits() {
+		return errBadBits
+	}
+	return checkProofOfWork(b, parent.CalcNextSeed())
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/bytom/protocol/bc"
+)
+
+// ValidationObserver receives callbacks as ValidateBlock/ValidateBlockHeader
+// progress, so callers can export per-stage timing (e.g. to Prometheus)
+// without patching this package. All methods must be safe to call from
+// whichever goroutine is validating a transaction, since ValidateBlock may
+// run with ValidationOptions.Parallel set.
+type ValidationObserver interface {
+	// OnHeaderChecked fires once ValidateBlockHeader has accepted b's
+	// header.
+	OnHeaderChecked(b *bc.Block)
+	// OnTxValidated fires after each transaction is validated, whether
+	// or not it passed.
+	OnTxValidated(index int, gasUsed int64, duration time.Duration)
+	// OnBlockRejected fires when ValidateBlock is about to return err.
+	OnBlockRejected(b *bc.Block, err error)
+}
+
+// NopObserver implements ValidationObserver by doing nothing. It's the
+// default used when a caller doesn't supply one.
+type NopObserver struct{}
+
+// OnHeaderChecked implements ValidationObserver.
+func (NopObserver) OnHeaderChecked(b *bc.Block) {}
+
+// OnTxValidated implements ValidationObserver.
+func (NopObserver) OnTxValidated(index int, gasUsed int64, duration time.Duration) {}
+
+// OnBlockRejected implements ValidationObserver.
+func (NopObserver) OnBlockRejected(b *bc.Block, err error) {}
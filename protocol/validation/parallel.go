@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/bytom/protocol/bc"
+	"github.com/bytom/protocol/bc/types"
+	"github.com/bytom/protocol/state"
+)
+
+// ValidationOptions controls how ValidateBlock validates the transactions
+// inside a block. The zero value validates sequentially, which is always
+// correct; Parallel trades extra goroutines for wall-clock time on large
+// blocks.
+type ValidationOptions struct {
+	// Parallel validates transactions on a worker pool instead of one at
+	// a time.
+	Parallel bool
+	// Workers is the pool size used when Parallel is set. A value <= 0
+	// defaults to runtime.NumCPU().
+	Workers int
+}
+
+// txResult is the outcome of validating a single transaction, kept so the
+// worker pool can report results in original transaction order.
+type txResult struct {
+	gasStatus *bc.GasState
+	err       error
+}
+
+// validateTxFn wraps ValidateTx, which takes only (tx, block) and is not
+// passed ctx. ctx is honored between transactions here in
+// validateTransactions, not inside ValidateTx itself. The wrapper exists so tests can swap in a
+// stand-in and exercise the sequential/parallel dispatch logic without
+// needing real transaction fixtures.
+var validateTxFn = func(ctx context.Context, tx *bc.Tx, b *bc.Block) (*bc.GasState, error) {
+	return ValidateTx(tx, b)
+}
+
+// validateTransactions validates the transactions in b, either on the
+// calling goroutine or fanned out across opts.Workers, and returns the
+// per-transaction results in b.Transactions order. It checks ctx.Done()
+// before starting each transaction; once it's cancelled, every transaction
+// not yet started is skipped and its result carries ctx's error.
+//
+// In sequential mode (the default) it stops at the first invalid
+// transaction, same as the original ValidateBlock: the returned slice is
+// shorter than b.Transactions when that happens. In parallel mode, every
+// transaction is dispatched and validated even after an earlier one has
+// failed; nothing stops the pool early. The caller still sees the first
+// failing index, it just costs more wall-clock on an adversarial block.
+func validateTransactions(ctx context.Context, b *bc.Block, opts ValidationOptions, observer ValidationObserver) []txResult {
+	results := make([]txResult, len(b.Transactions))
+
+	validateOne := func(i int) {
+		if err := ctx.Err(); err != nil {
+			results[i] = txResult{gasStatus: &bc.GasState{}, err: err}
+			return
+		}
+		start := time.Now()
+		gasStatus, err := validateTxFn(ctx, b.Transactions[i], b)
+		results[i] = txResult{gasStatus: gasStatus, err: err}
+		observer.OnTxValidated(i, gasStatus.GasUsed, time.Since(start))
+	}
+
+	if !opts.Parallel || len(b.Transactions) < 2 {
+		for i := range b.Transactions {
+			validateOne(i)
+			if !results[i].gasStatus.GasValid {
+				return results[:i+1]
+			}
+		}
+		return results
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(b.Transactions) {
+		workers = len(b.Transactions)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				validateOne(i)
+			}
+		}()
+	}
+	for i := range b.Transactions {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// ValidateBlockParallel validates a block the same way ValidateBlock does,
+// except that its transactions are checked on a worker pool (size
+// opts.Workers, default runtime.NumCPU()). Error semantics match
+// ValidateBlock exactly: the first failing transaction index, in block
+// order, is what gets reported, and TransactionStatus/merkle roots come
+// out identical to the sequential path.
+func ValidateBlockParallel(ctx context.Context, b *bc.Block, parent *state.BlockNode, block *types.Block, engine ConsensusEngine, opts ValidationOptions, observer ValidationObserver) error {
+	opts.Parallel = true
+	return validateBlock(ctx, b, parent, block, engine, opts, observer)
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bytom/errors"
+	"github.com/bytom/protocol/bc"
+)
+
+// fakeValidateTx returns a stand-in for ValidateTx whose outcome is
+// determined entirely by the transaction's position, so sequential and
+// parallel runs can be compared without real transaction fixtures.
+// failAt < 0 means every transaction passes.
+func fakeValidateTx(failAt int) func(ctx context.Context, tx *bc.Tx, block *bc.Block) (*bc.GasState, error) {
+	return func(ctx context.Context, tx *bc.Tx, block *bc.Block) (*bc.GasState, error) {
+		idx := int(tx.ID.V0)
+		gasStatus := &bc.GasState{GasValid: true, GasUsed: int64(idx + 1), BTMValue: uint64(idx)}
+		if failAt >= 0 && idx == failAt {
+			gasStatus.GasValid = false
+			return gasStatus, errors.New("fake validation failure")
+		}
+		return gasStatus, nil
+	}
+}
+
+func newTestBlock(n int) *bc.Block {
+	b := &bc.Block{}
+	for i := 0; i < n; i++ {
+		b.Transactions = append(b.Transactions, &bc.Tx{ID: bc.Hash{V0: uint64(i)}})
+	}
+	return b
+}
+
+func withFakeValidateTx(failAt int, fn func()) {
+	orig := validateTxFn
+	validateTxFn = fakeValidateTx(failAt)
+	defer func() { validateTxFn = orig }()
+	fn()
+}
+
+// TestValidateTransactionsParallelMatchesSequential confirms that running
+// the same block through the parallel and sequential paths produces
+// identical per-transaction results and TransactionStatus bitmaps.
+func TestValidateTransactionsParallelMatchesSequential(t *testing.T) {
+	withFakeValidateTx(-1, func() {
+		b := newTestBlock(32)
+		seq := validateTransactions(context.Background(), b, ValidationOptions{}, NopObserver{})
+		par := validateTransactions(context.Background(), b, ValidationOptions{Parallel: true, Workers: 8}, NopObserver{})
+
+		if len(seq) != len(par) {
+			t.Fatalf("result length mismatch: sequential %d, parallel %d", len(seq), len(par))
+		}
+
+		seqStatus := bc.NewTransactionStatus()
+		parStatus := bc.NewTransactionStatus()
+		for i := range seq {
+			if seq[i].gasStatus.GasUsed != par[i].gasStatus.GasUsed || seq[i].gasStatus.BTMValue != par[i].gasStatus.BTMValue {
+				t.Errorf("index %d: sequential %+v, parallel %+v", i, seq[i].gasStatus, par[i].gasStatus)
+			}
+			if (seq[i].err != nil) != (par[i].err != nil) {
+				t.Errorf("index %d: error mismatch, sequential %v, parallel %v", i, seq[i].err, par[i].err)
+			}
+			if err := seqStatus.SetStatus(i, seq[i].err != nil); err != nil {
+				t.Fatalf("SetStatus(sequential, %d): %s", i, err)
+			}
+			if err := parStatus.SetStatus(i, par[i].err != nil); err != nil {
+				t.Fatalf("SetStatus(parallel, %d): %s", i, err)
+			}
+		}
+
+		if !reflect.DeepEqual(seqStatus.VerifyStatus, parStatus.VerifyStatus) {
+			t.Errorf("TransactionStatus bitmap differs between sequential and parallel validation")
+		}
+	})
+}
+
+// TestValidateTransactionsReportsFirstFailure confirms that a failure
+// lands on the correct transaction index regardless of validation mode,
+// since ValidateBlock reports "first failing tx index" off this slice.
+func TestValidateTransactionsReportsFirstFailure(t *testing.T) {
+	const failAt = 5
+
+	withFakeValidateTx(failAt, func() {
+		b := newTestBlock(16)
+
+		for _, opts := range []ValidationOptions{{}, {Parallel: true, Workers: 4}} {
+			results := validateTransactions(context.Background(), b, opts, NopObserver{})
+			if results[failAt].gasStatus.GasValid {
+				t.Fatalf("opts=%+v: expected transaction %d to fail validation", opts, failAt)
+			}
+			for i, result := range results {
+				if i == failAt {
+					continue
+				}
+				if !result.gasStatus.GasValid {
+					t.Fatalf("opts=%+v: transaction %d unexpectedly failed", opts, i)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkValidateTransactionsSequential(b *testing.B) {
+	withFakeValidateTx(-1, func() {
+		block := newTestBlock(256)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			validateTransactions(context.Background(), block, ValidationOptions{}, NopObserver{})
+		}
+	})
+}
+
+func BenchmarkValidateTransactionsParallel(b *testing.B) {
+	withFakeValidateTx(-1, func() {
+		block := newTestBlock(256)
+		opts := ValidationOptions{Parallel: true}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			validateTransactions(context.Background(), block, opts, NopObserver{})
+		}
+	})
+}
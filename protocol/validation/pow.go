@@ -0,0 +1,17 @@
+package validation
+
+import (
+	"github.com/bytom/consensus/difficulty"
+	"github.com/bytom/protocol/bc"
+)
+
+// checkProofOfWork verifies that b's hash satisfies the target implied by
+// b.Bits, using the same Tensority/seed path the miner hashed against
+// when searching for a valid block. seed is whatever the parent block's
+// CalcNextSeed produced.
+func checkProofOfWork(b *bc.Block, seed *bc.Hash) error {
+	if !difficulty.CheckProofOfWork(&b.ID, seed, b.Bits) {
+		return errWorkProof
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bytom/consensus/difficulty"
+	"github.com/bytom/protocol/bc"
+)
+
+// TestCheckProofOfWork exercises checkProofOfWork's target comparison at
+// both extremes rather than against a recorded mainnet header: this
+// snapshot has no access to real chain data, and a hand-picked ID/seed
+// pair has no known relationship to what the real Tensority hash
+// function would produce for it, so there's no way to fabricate a
+// header that's actually known to pass. Instead bits is chosen so the
+// implied target is the maximum or minimum possible 256-bit value,
+// which makes the outcome certain by construction no matter what the
+// real hash turns out to be.
+func TestCheckProofOfWork(t *testing.T) {
+	id := bc.Hash{V0: 0x1111111111111111, V1: 0x2222222222222222, V2: 0x3333333333333333, V3: 0x4444444444444444}
+	seed := bc.Hash{V0: 0x5555555555555555, V1: 0x6666666666666666, V2: 0x7777777777777777, V3: 0x8888888888888888}
+
+	maxTarget := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	easyBits := difficulty.BigToCompact(maxTarget)
+	hardBits := difficulty.BigToCompact(big.NewInt(0))
+
+	easy := &bc.Block{BlockHeader: bc.BlockHeader{Bits: easyBits}}
+	easy.ID = id
+	if err := checkProofOfWork(easy, &seed); err != nil {
+		t.Errorf("a target at the maximum should accept any hash, got %s", err)
+	}
+
+	hard := &bc.Block{BlockHeader: bc.BlockHeader{Bits: hardBits}}
+	hard.ID = id
+	if err := checkProofOfWork(hard, &seed); err != errWorkProof {
+		t.Errorf("a target of zero should reject any non-zero hash, got %v", err)
+	}
+}